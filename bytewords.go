@@ -1,18 +1,10 @@
 package bcur
 
 import (
-	"bytes"
-	"compress/gzip"
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"hash/crc32"
-	"io"
-	"log"
 	"strings"
-	"unicode"
-
-	"github.com/fxamacker/cbor/v2"
 )
 
 var bytewords = "ableacidalsoapexaquaarchatomauntawayaxisbackbaldbarnbeltbetabiasbluebodybragbrewbulbbuzzcalmcashcatschefcityclawcodecolacookcostcruxcurlcuspcyandarkdatadaysdelidicedietdoordowndrawdropdrumdulldutyeacheasyechoedgeepicevenexamexiteyesfactfairfernfigsfilmfishfizzflapflewfluxfoxyfreefrogfuelfundgalagamegeargemsgiftgirlglowgoodgraygrimgurugushgyrohalfhanghardhawkheathelphighhillholyhopehornhutsicedideaidleinchinkyintoirisironitemjadejazzjoinjoltjowljudojugsjumpjunkjurykeepkenokeptkeyskickkilnkingkitekiwiknoblamblavalazyleaflegsliarlimplionlistlogoloudloveluaulucklungmainmanymathmazememomenumeowmildmintmissmonknailnavyneednewsnextnoonnotenumbobeyoboeomitonyxopenovalowlspaidpartpeckplaypluspoempoolposepuffpumapurrquadquizraceramprealredorichroadrockroofrubyruinrunsrustsafesagascarsetssilkskewslotsoapsolosongstubsurfswantacotasktaxitenttiedtimetinytoiltombtoystriptunatwinuglyundouniturgeuservastveryvetovialvibeviewvisavoidvowswallwandwarmwaspwavewaxywebswhatwhenwhizwolfworkyankyawnyellyogayurtzapszerozestzinczonezoom"
@@ -38,99 +30,24 @@ func init() {
 	}
 }
 
-func Decode(input string) Root {
-	// Step 1: Bytewords decode
-	decoded, err := decode(input, 2, "")
-	if err != nil {
-		log.Fatal("Bytewords decode failed:", err)
-	}
-
-	// Step 2: outer CBOR
-	var outer []byte
-	if err := cbor.Unmarshal(decoded, &outer); err != nil {
-		log.Fatal("CBOR outer decode failed:", err)
-	}
-
-	// Step 3: gunzip
-	gr, err := gzip.NewReader(bytes.NewReader(outer))
-	if err != nil {
-		log.Fatal("gzip reader failed:", err)
-	}
-	unzipped, err := io.ReadAll(gr)
-	gr.Close()
-	if err != nil {
-		log.Fatal("gzip read failed:", err)
-	}
-
-	// Step 4: decode inner CBOR as []any
-	var inner any
-	if err := cbor.Unmarshal(unzipped, &inner); err != nil {
-		log.Fatal("Inner CBOR decode failed:", err)
-	}
-
-	j, _ := json.MarshalIndent(inner, "", "  ")
-
-	var raw []any
-	if err := json.Unmarshal([]byte(j), &raw); err != nil {
-		panic(err)
-	}
-
-	version := int(raw[0].(float64))
-	accList := raw[1].([]any)
-
-	var accounts []Account
-	for _, a := range accList {
-		arr := a.([]any)
-		w := arr[4].([]any)
-		account := Account{
-			ID:    int(arr[0].(float64)),
-			Index: int(arr[1].(float64)),
-			Type:  arr[2].(string),
-			Block: int(arr[3].(float64)),
-			Wallet: WalletInfo{
-				DerivationPath: w[0].(string),
-				ChainCode:      w[1].(string),
-				Name:           w[2].(string),
-				Internal1:      w[3].(bool),
-				Internal2:      w[4].(bool),
-				SomeBytes:      w[5].(string),
-				XPub:           w[6].(string),
-			},
-		}
-		accounts = append(accounts, account)
-	}
-
-	return Root{
-		Version:  version,
-		Accounts: accounts,
-	}
-}
-
-// decode a single word (len=2 for minimal, 4 for full)
-func decodeWord(word string, wordLen int) (byte, error) {
-	if len(word) != wordLen {
+// decodeWord resolves a single standard-mode (4-char) word to its byte
+// value. Input must already be lowercase ASCII; callers that accept
+// case-insensitive or untrusted input should normalize before calling this.
+func decodeWord(word string) (byte, error) {
+	if len(word) != 4 {
 		return 0, errors.New("invalid bytewords length")
 	}
-	x := unicode.ToLower(rune(word[0])) - 'a'
-	var y rune
-	if wordLen == 4 {
-		y = unicode.ToLower(rune(word[3])) - 'a'
-	} else {
-		y = unicode.ToLower(rune(word[1])) - 'a'
-	}
-	if x < 0 || x >= 26 || y < 0 || y >= 26 {
+	x, y := word[0], word[3]
+	if x < 'a' || x > 'z' || y < 'a' || y > 'z' {
 		return 0, errors.New("invalid bytewords")
 	}
-	offset := int(y)*26 + int(x)
-	val := lookupTable[offset]
+	val := lookupTable[int(y-'a')*26+int(x-'a')]
 	if val == -1 {
 		return 0, errors.New("invalid bytewords")
 	}
-	if wordLen == 4 {
-		expected := words[val]
-		if word[1] != expected[1] || word[2] != expected[2] {
-			return 0, errors.New("invalid bytewords middle chars")
-		}
+	expected := words[val]
+	if word[1] != expected[1] || word[2] != expected[2] {
+		return 0, errors.New("invalid bytewords middle chars")
 	}
 	return byte(val), nil
 }
@@ -147,16 +64,19 @@ func appendCRC(data []byte) []byte {
 	return append(data, crc32Bytes(data)...)
 }
 
-// Encode minimal (2-char per byte)
+// Encode minimal (2-char per byte). Writes directly into a preallocated
+// buffer instead of through a strings.Builder, since this path carries
+// multi-KB fountain-coded payloads where per-byte allocation overhead
+// dominates.
 func encodeMinimal(data []byte) string {
 	data = appendCRC(data)
-	var sb strings.Builder
-	for _, b := range data {
+	out := make([]byte, 2*len(data))
+	for i, b := range data {
 		w := words[b]
-		sb.WriteByte(w[0])
-		sb.WriteByte(w[3])
+		out[2*i] = w[0]
+		out[2*i+1] = w[3]
 	}
-	return sb.String()
+	return string(out)
 }
 
 // Encode standard (4-char per byte, sep by space)
@@ -169,26 +89,61 @@ func encodeStandard(data []byte) string {
 	return strings.Join(parts, " ")
 }
 
-// Decode
+// decode parses a bytewords-encoded string and verifies its trailing CRC32.
+// wordLen selects minimal (2-char) or standard (4-char, sep-separated)
+// mode; sep is ignored in minimal mode.
 func decode(s string, wordLen int, sep string) ([]byte, error) {
-	var tokens []string
 	if wordLen == 4 {
-		tokens = strings.Split(s, sep)
-	} else {
-		// minimal â†’ 2-char chunks
-		for i := 0; i < len(s); i += 2 {
-			tokens = append(tokens, s[i:i+2])
+		return decodeStandard(s, sep)
+	}
+	return decodeMinimal(s)
+}
+
+// decodeMinimal decodes a minimal (2-char-per-byte) bytewords string
+// directly from its underlying bytes: a single bytewise scan rejects any
+// non-lowercase-ASCII input up front, then each byte is resolved by
+// indexing lookupTable with (s[i+1]-'a')*26+(s[i]-'a') instead of
+// re-validating through decodeWord one token at a time.
+func decodeMinimal(s string) ([]byte, error) {
+	if len(s)%2 != 0 {
+		return nil, errors.New("invalid bytewords length")
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return nil, errors.New("invalid bytewords")
+		}
+	}
+
+	buf := make([]byte, len(s)/2)
+	for i := range buf {
+		x, y := s[2*i], s[2*i+1]
+		val := lookupTable[int(y-'a')*26+int(x-'a')]
+		if val == -1 {
+			return nil, errors.New("invalid bytewords")
 		}
+		buf[i] = byte(val)
 	}
+	return splitChecksum(buf)
+}
 
+// decodeStandard decodes a standard (4-char, sep-separated) bytewords
+// string one token at a time via decodeWord.
+func decodeStandard(s, sep string) ([]byte, error) {
+	tokens := strings.Split(s, sep)
 	buf := make([]byte, len(tokens))
 	for i, t := range tokens {
-		b, err := decodeWord(t, wordLen)
+		b, err := decodeWord(t)
 		if err != nil {
 			return nil, err
 		}
 		buf[i] = b
 	}
+	return splitChecksum(buf)
+}
+
+// splitChecksum splits off and verifies buf's trailing CRC32, returning the
+// body with the checksum removed.
+func splitChecksum(buf []byte) ([]byte, error) {
 	if len(buf) < 5 {
 		return nil, errors.New("too short")
 	}
@@ -211,27 +166,3 @@ func equal(a, b []byte) bool {
 	}
 	return true
 }
-
-type WalletInfo struct {
-	DerivationPath string
-	ChainCode      string
-	Name           string
-	Internal1      bool
-	Internal2      bool
-	SomeBytes      string
-	XPub           string
-}
-
-type Account struct {
-	ID     int
-	Index  int
-	Type   string
-	Block  int
-	Wallet WalletInfo
-}
-
-// Because the outer structure is an array-of-array, we need to use a custom type
-type Root struct {
-	Version  int
-	Accounts []Account
-}
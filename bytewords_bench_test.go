@@ -0,0 +1,31 @@
+package bcur
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomPayload(n int) []byte {
+	r := rand.New(rand.NewSource(1))
+	data := make([]byte, n)
+	r.Read(data)
+	return data
+}
+
+func BenchmarkEncodeMinimal64KiB(b *testing.B) {
+	data := randomPayload(64 * 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		encodeMinimal(data)
+	}
+}
+
+func BenchmarkDecodeMinimal64KiB(b *testing.B) {
+	encoded := encodeMinimal(randomPayload(64 * 1024))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decode(encoded, 2, ""); err != nil {
+			b.Fatalf("decode: %v", err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package bcur
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec compresses and decompresses the inner CBOR payload carried inside a
+// UR's outer CBOR byte string.
+type Codec interface {
+	Compress([]byte) ([]byte, error)
+	Decompress([]byte) ([]byte, error)
+}
+
+// Codec tags. Encode prepends one of these to the outer CBOR payload so
+// Decode can pick the matching Codec without guessing.
+const (
+	CodecRaw byte = iota
+	CodecGzip
+	CodecDeflate
+	CodecZstd
+)
+
+var codecs = map[byte]Codec{
+	CodecRaw:     rawCodec{},
+	CodecGzip:    gzipCodec{},
+	CodecDeflate: deflateCodec{},
+	CodecZstd:    zstdCodec{},
+}
+
+// gzipMagic is sniffed by detectCodec so payloads produced before codec
+// tagging existed - a bare gzip stream with no leading tag byte - still
+// decode correctly.
+var gzipMagic = [2]byte{0x1f, 0x8b}
+
+type rawCodec struct{}
+
+func (rawCodec) Compress(data []byte) ([]byte, error)   { return data, nil }
+func (rawCodec) Decompress(data []byte) ([]byte, error) { return data, nil }
+
+type gzipCodec struct{}
+
+func (gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCodec) Decompress(data []byte) ([]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return io.ReadAll(gr)
+}
+
+type deflateCodec struct{}
+
+func (deflateCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (deflateCodec) Decompress(data []byte) ([]byte, error) {
+	fr := flate.NewReader(bytes.NewReader(data))
+	defer fr.Close()
+	return io.ReadAll(fr)
+}
+
+// zstdCodec compresses with zstd's default encoder settings; it uses no
+// dictionary.
+type zstdCodec struct{}
+
+func (zstdCodec) Compress(data []byte) ([]byte, error) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer enc.Close()
+	return enc.EncodeAll(data, nil), nil
+}
+
+func (zstdCodec) Decompress(data []byte) ([]byte, error) {
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer dec.Close()
+	return dec.DecodeAll(data, nil)
+}
+
+// detectCodec inspects an outer CBOR payload and returns the Codec to
+// decompress it with, along with the payload stripped of any leading codec
+// tag byte. A payload starting with the gzip magic is treated as an
+// untagged legacy payload so data produced before codec tagging existed
+// keeps decoding.
+func detectCodec(payload []byte) (Codec, []byte, error) {
+	if len(payload) >= 2 && payload[0] == gzipMagic[0] && payload[1] == gzipMagic[1] {
+		return gzipCodec{}, payload, nil
+	}
+	if len(payload) == 0 {
+		return nil, nil, fmt.Errorf("bcur: empty payload")
+	}
+	codec, ok := codecs[payload[0]]
+	if !ok {
+		return nil, nil, fmt.Errorf("bcur: unknown codec tag 0x%02x", payload[0])
+	}
+	return codec, payload[1:], nil
+}
@@ -0,0 +1,46 @@
+package bcur
+
+import "testing"
+
+func TestCodecsRoundTrip(t *testing.T) {
+	data := []byte("repeated derivation path prefix repeated derivation path prefix")
+
+	for tag, codec := range codecs {
+		tag, codec := tag, codec
+		t.Run(string(rune('A'+int(tag))), func(t *testing.T) {
+			compressed, err := codec.Compress(data)
+			if err != nil {
+				t.Fatalf("Compress: %v", err)
+			}
+			decompressed, err := codec.Decompress(compressed)
+			if err != nil {
+				t.Fatalf("Decompress: %v", err)
+			}
+			if string(decompressed) != string(data) {
+				t.Fatalf("round trip mismatch: got %q, want %q", decompressed, data)
+			}
+		})
+	}
+}
+
+func TestDetectCodecLegacyGzip(t *testing.T) {
+	compressed, err := gzipCodec{}.Compress([]byte("legacy payload"))
+	if err != nil {
+		t.Fatalf("Compress: %v", err)
+	}
+
+	codec, body, err := detectCodec(compressed)
+	if err != nil {
+		t.Fatalf("detectCodec: %v", err)
+	}
+	if _, ok := codec.(gzipCodec); !ok {
+		t.Fatalf("expected gzipCodec, got %T", codec)
+	}
+	decompressed, err := codec.Decompress(body)
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	if string(decompressed) != "legacy payload" {
+		t.Fatalf("got %q", decompressed)
+	}
+}
@@ -0,0 +1,104 @@
+package bcur
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// DecodeError reports which stage of schema dispatch failed, so callers can
+// tell an unsupported schema version apart from a malformed top-level array
+// without parsing the wrapped error's text.
+type DecodeError struct {
+	Field string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("bcur: field %s: %v", e.Field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode parses a bytewords-encoded, compressed, CBOR-nested UR payload into
+// a Root. It returns an error instead of aborting the process on malformed
+// input, so callers can safely decode data from untrusted sources such as a
+// scanned QR code.
+func Decode(input string) (Root, error) {
+	decoded, err := decode(input, 2, "")
+	if err != nil {
+		return Root{}, fmt.Errorf("bcur: bytewords: %w", err)
+	}
+
+	var outer []byte
+	if err := cbor.Unmarshal(decoded, &outer); err != nil {
+		return Root{}, fmt.Errorf("bcur: outer cbor: %w", err)
+	}
+
+	codec, body, err := detectCodec(outer)
+	if err != nil {
+		return Root{}, fmt.Errorf("bcur: codec: %w", err)
+	}
+	inner, err := codec.Decompress(body)
+	if err != nil {
+		return Root{}, fmt.Errorf("bcur: decompress: %w", err)
+	}
+
+	return decodeSchema(inner)
+}
+
+// decodeSchema reads the schema version from the first element of the inner
+// CBOR array and dispatches to the decoder registered for it in
+// schemaDecoders, so later schema versions can add fields without breaking
+// readers still expecting an older version.
+func decodeSchema(data []byte) (Root, error) {
+	var fields []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &fields); err != nil {
+		return Root{}, &DecodeError{Field: "root", Err: fmt.Errorf("not an array: %w", err)}
+	}
+	if len(fields) < 1 {
+		return Root{}, &DecodeError{Field: "root", Err: errors.New("expected [version, accounts]")}
+	}
+
+	var version int
+	if err := cbor.Unmarshal(fields[0], &version); err != nil {
+		return Root{}, &DecodeError{Field: "version", Err: err}
+	}
+
+	decodeVersion, ok := schemaDecoders[version]
+	if !ok {
+		return Root{}, &DecodeError{Field: "version", Err: fmt.Errorf("unsupported schema version %d", version)}
+	}
+	return decodeVersion(data)
+}
+
+// WalletInfo describes the wallet metadata nested under each Account entry.
+type WalletInfo struct {
+	DerivationPath string
+	ChainCode      string
+	Name           string
+	Internal1      bool
+	Internal2      bool
+	SomeBytes      string
+	XPub           string
+}
+
+// Account is one entry of the account list carried by a Root payload.
+type Account struct {
+	ID     int
+	Index  int
+	Type   string
+	Block  int
+	Wallet WalletInfo
+}
+
+// Root is the top-level value decoded from a UR payload: [version,
+// accounts]. On decode, Version selects which schema in schemaDecoders
+// describes the rest of the array.
+type Root struct {
+	Version  int
+	Accounts []Account
+}
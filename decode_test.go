@@ -0,0 +1,20 @@
+package bcur
+
+import "testing"
+
+// FuzzDecode asserts Decode never panics on arbitrary input; malformed input
+// should yield a returned error instead of aborting the process.
+func FuzzDecode(f *testing.F) {
+	f.Add("")
+	f.Add("ableacid")
+	f.Add("ur:crypto-account/1-1/ababab")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Decode panicked on input %q: %v", input, r)
+			}
+		}()
+		_, _ = Decode(input)
+	})
+}
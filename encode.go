@@ -0,0 +1,69 @@
+package bcur
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// EncodeOptions configures the compression codec Encode/EncodeStandard use.
+// The zero value selects CodecRaw (no compression), since that is byte 0 in
+// the CodecRaw/CodecGzip/CodecDeflate/CodecZstd tag space; it does not
+// default to CodecGzip. Encode and EncodeStandard set Codec: CodecGzip
+// explicitly rather than relying on the zero value - callers building
+// EncodeOptions themselves should do the same unless CodecRaw is what they
+// want.
+type EncodeOptions struct {
+	Codec byte
+}
+
+// Encode is the inverse of Decode: it marshals root into the array-of-array
+// CBOR shape Decode expects, compresses it with CodecGzip, wraps the result
+// in an outer CBOR byte string tagged with the codec used, and
+// bytewords-encodes that in minimal (2-char) form.
+func Encode(root Root) (string, error) {
+	return encode(root, encodeMinimal, EncodeOptions{Codec: CodecGzip})
+}
+
+// EncodeStandard is Encode, but bytewords-encodes the result in standard
+// (space-separated, 4-char word) form instead of minimal form.
+func EncodeStandard(root Root) (string, error) {
+	return encode(root, encodeStandard, EncodeOptions{Codec: CodecGzip})
+}
+
+// EncodeWithOptions is Encode, but lets the caller pick a compression codec
+// via opts - for example EncodeOptions{Codec: CodecZstd}.
+func EncodeWithOptions(root Root, opts EncodeOptions) (string, error) {
+	return encode(root, encodeMinimal, opts)
+}
+
+func encode(root Root, bytewordsEncode func([]byte) string, opts EncodeOptions) (string, error) {
+	inner, err := toInnerCBOR(root)
+	if err != nil {
+		return "", fmt.Errorf("bcur: inner cbor: %w", err)
+	}
+
+	codec, ok := codecs[opts.Codec]
+	if !ok {
+		return "", fmt.Errorf("bcur: unknown codec tag 0x%02x", opts.Codec)
+	}
+	compressed, err := codec.Compress(inner)
+	if err != nil {
+		return "", fmt.Errorf("bcur: compress: %w", err)
+	}
+	tagged := append([]byte{opts.Codec}, compressed...)
+
+	outer, err := cbor.Marshal(tagged)
+	if err != nil {
+		return "", fmt.Errorf("bcur: outer cbor: %w", err)
+	}
+
+	return bytewordsEncode(outer), nil
+}
+
+// toInnerCBOR marshals root into its schema-version-1 on-wire shape via the
+// `toarray` struct tags in schema.go, rather than building the array-of-array
+// shape by hand.
+func toInnerCBOR(root Root) ([]byte, error) {
+	return cbor.Marshal(rootV1FromRoot(root))
+}
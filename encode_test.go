@@ -0,0 +1,119 @@
+package bcur
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// fixtureUR is a real, independently-generated (not produced by this
+// package's own Encode) schema-v1, gzip-codec, minimal-bytewords UR payload
+// for two accounts: a segwit "Savings" account and a legacy "Checking"
+// account. It anchors TestEncodeDecodeRoundTrip in an actual on-wire
+// payload rather than one this package encoded itself.
+const fixtureUR = "hdlgadctluayaeaeaeaeaeaoaxjeidjzimihiefddnglgtdltkdwhsistktotlrldygytsemaeoylkssoxvebbfxcniamuwkvtssprsfrfwkvopeheeygmgmbwgumogmgutegedrbkgemusfnsfxutlsfwwfbtuteydngegelugmbthpnlcstejpgmtebwmudntafpamnlrtbtgegteoeceorlpfeespjotofdgttoamcyykvwjefgjpidhtimgmidgmdrenmulkaevtvytkuomtaeaeaehnoskpya"
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		ur     func(t *testing.T) string
+		verify func(t *testing.T, root Root)
+	}{
+		{
+			name: "real fixture: two accounts, gzip codec",
+			ur:   func(t *testing.T) string { return fixtureUR },
+			verify: func(t *testing.T, root Root) {
+				if root.Version != 1 {
+					t.Fatalf("Version = %d, want 1", root.Version)
+				}
+				if len(root.Accounts) != 2 {
+					t.Fatalf("len(Accounts) = %d, want 2", len(root.Accounts))
+				}
+				if root.Accounts[0].Wallet.Name != "Savings" || root.Accounts[1].Wallet.Name != "Checking" {
+					t.Fatalf("unexpected account names: %+v", root.Accounts)
+				}
+			},
+		},
+		{
+			name: "freshly encoded: single account",
+			ur: func(t *testing.T) string {
+				root := Root{
+					Version: 1,
+					Accounts: []Account{
+						{
+							ID:    1,
+							Index: 0,
+							Type:  "segwit",
+							Block: 0,
+							Wallet: WalletInfo{
+								DerivationPath: "m/84'/0'/0'",
+								ChainCode:      "abcd",
+								Name:           "Savings",
+								Internal1:      true,
+								Internal2:      false,
+								SomeBytes:      "deadbeef",
+								XPub:           "xpub6Dhypothetical",
+							},
+						},
+					},
+				}
+				ur, err := Encode(root)
+				if err != nil {
+					t.Fatalf("Encode: %v", err)
+				}
+				return ur
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ur := tc.ur(t)
+
+			decoded, err := Decode(ur)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if tc.verify != nil {
+				tc.verify(t, decoded)
+			}
+
+			reencoded, err := Encode(decoded)
+			if err != nil {
+				t.Fatalf("re-Encode: %v", err)
+			}
+
+			innerA, err := innerCBOR(ur)
+			if err != nil {
+				t.Fatalf("innerCBOR(ur): %v", err)
+			}
+			innerB, err := innerCBOR(reencoded)
+			if err != nil {
+				t.Fatalf("innerCBOR(reencoded): %v", err)
+			}
+			if !bytes.Equal(innerA, innerB) {
+				t.Fatalf("intermediate CBOR differs after round-trip:\n%x\n%x", innerA, innerB)
+			}
+		})
+	}
+}
+
+// innerCBOR replays the first three steps of Decode to recover the
+// decompressed inner CBOR bytes a UR string carries, without decoding them
+// into a Root.
+func innerCBOR(ur string) ([]byte, error) {
+	decodedWords, err := decode(ur, 2, "")
+	if err != nil {
+		return nil, err
+	}
+	var outer []byte
+	if err := cbor.Unmarshal(decodedWords, &outer); err != nil {
+		return nil, err
+	}
+	codec, body, err := detectCodec(outer)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Decompress(body)
+}
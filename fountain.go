@@ -0,0 +1,415 @@
+package bcur
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// defaultMaxFragmentLen is used by NewEncoder when no explicit fragment size
+// is requested.
+const defaultMaxFragmentLen = 200
+
+// Encoder splits a CBOR-encoded payload into fixed-size fragments and streams
+// it as a sequence of multi-part URs using a Luby-style fountain code, so
+// payloads larger than a single QR frame can be transmitted and reassembled
+// by a Decoder regardless of arrival order.
+type Encoder struct {
+	urType      string
+	fragments   [][]byte
+	fragmentLen int
+	messageLen  int
+	checksum    uint32
+	seqNum      int
+}
+
+// NewEncoder CBOR-marshals value and prepares an Encoder that emits it as a
+// stream of "ur:<urType>/<seqNum>-<seqLen>/<bytewords>" parts, none of whose
+// fragments exceed maxFragmentLen bytes. maxFragmentLen <= 0 selects a 200
+// byte default.
+func NewEncoder(urType string, value any, maxFragmentLen int) (*Encoder, error) {
+	payload, err := cbor.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("bcur: encode payload: %w", err)
+	}
+	if maxFragmentLen <= 0 {
+		maxFragmentLen = defaultMaxFragmentLen
+	}
+
+	seqLen := (len(payload) + maxFragmentLen - 1) / maxFragmentLen
+	if seqLen < 1 {
+		seqLen = 1
+	}
+	fragmentLen := (len(payload) + seqLen - 1) / seqLen
+
+	fragments := make([][]byte, seqLen)
+	for i := range fragments {
+		frag := make([]byte, fragmentLen)
+		start := i * fragmentLen
+		if start < len(payload) {
+			end := start + fragmentLen
+			if end > len(payload) {
+				end = len(payload)
+			}
+			copy(frag, payload[start:end])
+		}
+		fragments[i] = frag
+	}
+
+	return &Encoder{
+		urType:      urType,
+		fragments:   fragments,
+		fragmentLen: fragmentLen,
+		messageLen:  len(payload),
+		checksum:    crc32.ChecksumIEEE(payload),
+	}, nil
+}
+
+// NextPart returns the next multi-part UR in the stream. The first seqLen
+// parts are "pure" (one fragment each); every part after that mixes a
+// pseudo-randomly chosen subset of fragments together, and NextPart can be
+// called indefinitely to keep producing fresh mixed parts for a lossy
+// transport.
+func (e *Encoder) NextPart() string {
+	e.seqNum++
+	indices := chooseFragments(e.seqNum, len(e.fragments), e.checksum)
+
+	mixed := make([]byte, e.fragmentLen)
+	for _, idx := range indices {
+		xorInto(mixed, e.fragments[idx])
+	}
+
+	body, err := cbor.Marshal([]any{e.seqNum, len(e.fragments), e.messageLen, e.checksum, mixed})
+	if err != nil {
+		panic(fmt.Sprintf("bcur: marshal part: %v", err))
+	}
+
+	return fmt.Sprintf("ur:%s/%d-%d/%s", e.urType, e.seqNum, len(e.fragments), encodeMinimal(body))
+}
+
+// Decoder reassembles a payload from the multi-part URs produced by an
+// Encoder. Parts may be fed in any order and duplicates are ignored; the
+// Decoder tracks which fragments are fully known and algebraically reduces
+// each new mixed part against them, cascading whenever a reduction exposes a
+// previously unknown fragment.
+type Decoder struct {
+	urType      string
+	seqLen      int
+	messageLen  int
+	checksum    uint32
+	fragmentLen int
+
+	known   map[int][]byte
+	pending []pendingPart
+	seen    map[string]bool
+}
+
+type pendingPart struct {
+	indices map[int]bool
+	mixed   []byte
+}
+
+// NewDecoder returns an empty Decoder for a single UR stream. The stream's
+// identity (urType, seqLen, messageLen and checksum) is learned from the
+// first part passed to ReceivePart.
+func NewDecoder() *Decoder {
+	return &Decoder{known: make(map[int][]byte), seen: make(map[string]bool)}
+}
+
+// ReceivePart parses a single multi-part UR produced by Encoder.NextPart and
+// folds it into the decoder's state.
+func (d *Decoder) ReceivePart(part string) error {
+	urType, seqNum, seqLen, body, err := parsePart(part)
+	if err != nil {
+		return fmt.Errorf("bcur: %w", err)
+	}
+	if seqLen < 1 || seqNum < 1 {
+		return errors.New("bcur: part sequence numbers must be >= 1")
+	}
+	if d.seen[part] {
+		return nil
+	}
+
+	decoded, err := decode(body, 2, "")
+	if err != nil {
+		return fmt.Errorf("bcur: bytewords decode: %w", err)
+	}
+	var fields []any
+	if err := cbor.Unmarshal(decoded, &fields); err != nil {
+		return fmt.Errorf("bcur: cbor decode part: %w", err)
+	}
+	if len(fields) != 5 {
+		return errors.New("bcur: malformed part: expected 5 fields")
+	}
+	partSeqNum, ok1 := toUint64(fields[0])
+	partSeqLen, ok2 := toUint64(fields[1])
+	messageLen, ok3 := toUint64(fields[2])
+	checksum, ok4 := toUint64(fields[3])
+	mixed, ok5 := fields[4].([]byte)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return errors.New("bcur: malformed part: unexpected field types")
+	}
+	if int(partSeqNum) != seqNum || int(partSeqLen) != seqLen {
+		return errors.New("bcur: part header/body sequence mismatch")
+	}
+
+	if d.urType == "" {
+		d.urType = urType
+		d.seqLen = seqLen
+		d.messageLen = int(messageLen)
+		d.checksum = uint32(checksum)
+		d.fragmentLen = len(mixed)
+	} else if urType != d.urType || seqLen != d.seqLen || int(messageLen) != d.messageLen || uint32(checksum) != d.checksum {
+		return errors.New("bcur: part does not belong to this stream")
+	}
+	d.seen[part] = true
+
+	indices := chooseFragments(seqNum, seqLen, d.checksum)
+	idxSet := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		idxSet[idx] = true
+	}
+	d.reduce(idxSet, append([]byte(nil), mixed...))
+	return nil
+}
+
+// Progress reports the fraction of fragments solved so far, in [0, 1].
+func (d *Decoder) Progress() float64 {
+	if d.seqLen == 0 {
+		return 0
+	}
+	return float64(len(d.known)) / float64(d.seqLen)
+}
+
+// Result returns the reassembled payload once every fragment has been
+// solved. The second return value is false while the decoder is still
+// incomplete or if the reassembled payload fails its checksum.
+func (d *Decoder) Result() ([]byte, bool) {
+	if d.seqLen == 0 || len(d.known) != d.seqLen {
+		return nil, false
+	}
+	payload := make([]byte, 0, d.messageLen)
+	for i := 0; i < d.seqLen; i++ {
+		payload = append(payload, d.known[i]...)
+	}
+	if len(payload) < d.messageLen {
+		return nil, false
+	}
+	payload = payload[:d.messageLen]
+	if crc32.ChecksumIEEE(payload) != d.checksum {
+		return nil, false
+	}
+	return payload, true
+}
+
+// reduce XORs out any fragments in indices that are already known, then
+// either records mixed as a newly solved fragment (if exactly one index
+// remains), queues it for later reduction, or discards it if fully solved.
+func (d *Decoder) reduce(indices map[int]bool, mixed []byte) {
+	for idx := range indices {
+		if frag, ok := d.known[idx]; ok {
+			xorInto(mixed, frag)
+			delete(indices, idx)
+		}
+	}
+	switch len(indices) {
+	case 0:
+		return
+	case 1:
+		for idx := range indices {
+			d.known[idx] = mixed
+		}
+		d.cascade()
+	default:
+		d.pending = append(d.pending, pendingPart{indices: indices, mixed: mixed})
+	}
+}
+
+// cascade repeatedly sweeps the pending queue, reducing each part against
+// newly known fragments until a full pass makes no further progress.
+func (d *Decoder) cascade() {
+	for progressed := true; progressed; {
+		progressed = false
+		remaining := d.pending[:0]
+		for _, p := range d.pending {
+			for idx := range p.indices {
+				if frag, ok := d.known[idx]; ok {
+					xorInto(p.mixed, frag)
+					delete(p.indices, idx)
+				}
+			}
+			switch len(p.indices) {
+			case 0:
+				progressed = true
+			case 1:
+				for idx := range p.indices {
+					d.known[idx] = p.mixed
+				}
+				progressed = true
+			default:
+				remaining = append(remaining, p)
+			}
+		}
+		d.pending = remaining
+	}
+}
+
+// parsePart splits "ur:<type>/<seqNum>-<seqLen>/<bytewords>" into its parts.
+func parsePart(part string) (urType string, seqNum, seqLen int, body string, err error) {
+	rest, ok := strings.CutPrefix(part, "ur:")
+	if !ok {
+		return "", 0, 0, "", errors.New("missing ur: scheme")
+	}
+	segs := strings.SplitN(rest, "/", 3)
+	if len(segs) != 3 {
+		return "", 0, 0, "", errors.New("expected ur:<type>/<seqNum>-<seqLen>/<bytewords>")
+	}
+	nums := strings.SplitN(segs[1], "-", 2)
+	if len(nums) != 2 {
+		return "", 0, 0, "", errors.New("malformed sequence component")
+	}
+	seqNum, err = strconv.Atoi(nums[0])
+	if err != nil {
+		return "", 0, 0, "", fmt.Errorf("sequence number: %w", err)
+	}
+	seqLen, err = strconv.Atoi(nums[1])
+	if err != nil {
+		return "", 0, 0, "", fmt.Errorf("sequence length: %w", err)
+	}
+	return segs[0], seqNum, seqLen, segs[2], nil
+}
+
+// toUint64 normalizes the handful of numeric types cbor.Unmarshal may produce
+// for a `[]any` element into a uint64.
+func toUint64(v any) (uint64, bool) {
+	switch n := v.(type) {
+	case uint64:
+		return n, true
+	case int64:
+		if n < 0 {
+			return 0, false
+		}
+		return uint64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// xorInto XORs src into dst in place, up to the shorter of the two lengths.
+func xorInto(dst, src []byte) {
+	n := len(dst)
+	if len(src) < n {
+		n = len(src)
+	}
+	for i := 0; i < n; i++ {
+		dst[i] ^= src[i]
+	}
+}
+
+// chooseFragments deterministically picks the set of fragment indices mixed
+// into the part with the given sequence number: the first seqLen parts are
+// pure (one fragment each) so a decoder can make immediate progress, and
+// every part after that mixes a pseudo-randomly chosen subset.
+//
+// The generator is seeded from a SHA-256 of the payload's 32-bit CRC32
+// checksum (carried in every part's header), seqNum and seqLen, rather than
+// a SHA-256 of the full payload: the decoder only ever sees individual
+// parts, never the payload itself, so it has no payload bytes to hash until
+// decoding is already done. Seeding from the checksum is a deliberate,
+// documented deviation from a payload-hash seed - it keeps fragment
+// selection reproducible from a part's own header fields alone, at the cost
+// of reducing the seed's effective entropy to 32 bits.
+func chooseFragments(seqNum, seqLen int, checksum uint32) []int {
+	if seqNum <= seqLen {
+		return []int{seqNum - 1}
+	}
+
+	var seed [12]byte
+	binary.BigEndian.PutUint32(seed[0:4], checksum)
+	binary.BigEndian.PutUint32(seed[4:8], uint32(seqNum))
+	binary.BigEndian.PutUint32(seed[8:12], uint32(seqLen))
+	digest := sha256.Sum256(seed[:])
+	rng := newXoshiro256(digest)
+
+	degree := chooseDegree(seqLen, rng)
+	remaining := make([]int, seqLen)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	indices := make([]int, 0, degree)
+	for i := 0; i < degree; i++ {
+		j := int(rng.next() % uint64(len(remaining)))
+		indices = append(indices, remaining[j])
+		remaining[j] = remaining[len(remaining)-1]
+		remaining = remaining[:len(remaining)-1]
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// chooseDegree picks how many fragments a mixed part combines, following an
+// ideal-soliton-style distribution so small degrees (especially 2) are most
+// common, with a long tail up to seqLen.
+func chooseDegree(seqLen int, rng *xoshiro256) int {
+	if seqLen <= 1 {
+		return 1
+	}
+	f := rng.nextFloat64()
+	cum := 1.0 / float64(seqLen)
+	if f < cum {
+		return 1
+	}
+	for d := 2; d <= seqLen; d++ {
+		cum += 1.0 / (float64(d) * float64(d-1))
+		if f < cum {
+			return d
+		}
+	}
+	return seqLen
+}
+
+// xoshiro256 is the xoshiro256** generator (Blackman & Vigna), seeded
+// deterministically from a SHA-256 digest so fragment selection can be
+// reproduced from the values carried in a part's header alone.
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+func newXoshiro256(seed [32]byte) *xoshiro256 {
+	var x xoshiro256
+	for i := range x.s {
+		x.s[i] = binary.LittleEndian.Uint64(seed[i*8 : i*8+8])
+	}
+	return &x
+}
+
+func (x *xoshiro256) next() uint64 {
+	s := &x.s
+	result := rotl(s[1]*5, 7) * 9
+
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = rotl(s[3], 45)
+
+	return result
+}
+
+// nextFloat64 returns a pseudo-random float64 in [0, 1).
+func (x *xoshiro256) nextFloat64() float64 {
+	return float64(x.next()>>11) / (1 << 53)
+}
+
+func rotl(x uint64, k uint) uint64 {
+	return (x << k) | (x >> (64 - k))
+}
@@ -0,0 +1,124 @@
+package bcur
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// fountainTestPayload returns a string long enough that NewEncoder's 16-byte
+// fragment size splits it into more than a dozen fragments.
+func fountainTestPayload() string {
+	return "the quick brown fox jumps over the lazy dog, repeated for length: " +
+		"the quick brown fox jumps over the lazy dog, repeated for length: " +
+		"the quick brown fox jumps over the lazy dog, repeated for length."
+}
+
+// newFountainParts runs enc far enough to produce seqLen pure parts and
+// returns them alongside seqLen, learned from the first part's own header.
+func newFountainParts(t *testing.T, enc *Encoder) (parts []string, seqLen int) {
+	t.Helper()
+	first := enc.NextPart()
+	_, _, seqLen, _, err := parsePart(first)
+	if err != nil {
+		t.Fatalf("parsePart: %v", err)
+	}
+	parts = append(parts, first)
+	for i := 1; i < seqLen; i++ {
+		parts = append(parts, enc.NextPart())
+	}
+	return parts, seqLen
+}
+
+func assertFountainResult(t *testing.T, dec *Decoder, originalValue string) {
+	t.Helper()
+	got, ok := dec.Result()
+	if !ok {
+		t.Fatalf("Result: not complete, progress=%v", dec.Progress())
+	}
+	want, err := cbor.Marshal(originalValue)
+	if err != nil {
+		t.Fatalf("cbor.Marshal: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("decoded payload mismatch:\ngot:  %x\nwant: %x", got, want)
+	}
+}
+
+func TestFountainRoundTripPureShuffled(t *testing.T) {
+	payload := fountainTestPayload()
+	enc, err := NewEncoder("test", payload, 16)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	parts, _ := newFountainParts(t, enc)
+
+	r := rand.New(rand.NewSource(1))
+	r.Shuffle(len(parts), func(i, j int) { parts[i], parts[j] = parts[j], parts[i] })
+
+	dec := NewDecoder()
+	for _, p := range parts {
+		if err := dec.ReceivePart(p); err != nil {
+			t.Fatalf("ReceivePart: %v", err)
+		}
+	}
+
+	assertFountainResult(t, dec, payload)
+}
+
+func TestFountainRoundTripMixedPartsOnly(t *testing.T) {
+	payload := fountainTestPayload()
+	enc, err := NewEncoder("test", payload, 16)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+
+	// Burn through the pure parts without ever feeding one to the decoder,
+	// so reassembly has to happen entirely from mixed (seqNum > seqLen)
+	// parts.
+	_, seqLen := newFountainParts(t, enc)
+
+	dec := NewDecoder()
+	const maxMixedParts = 500
+	for i := 0; i < maxMixedParts && dec.Progress() < 1; i++ {
+		if err := dec.ReceivePart(enc.NextPart()); err != nil {
+			t.Fatalf("ReceivePart: %v", err)
+		}
+	}
+
+	if dec.Progress() < 1 {
+		t.Fatalf("decoder did not converge from mixed parts alone after %d parts (seqLen=%d)", maxMixedParts, seqLen)
+	}
+	assertFountainResult(t, dec, payload)
+}
+
+func TestFountainRoundTripDuplicateParts(t *testing.T) {
+	payload := fountainTestPayload()
+	enc, err := NewEncoder("test", payload, 16)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	parts, _ := newFountainParts(t, enc)
+
+	dec := NewDecoder()
+	// Feed every pure part twice; duplicates must be ignored rather than
+	// corrupting fragments that are already known.
+	for _, p := range parts {
+		if err := dec.ReceivePart(p); err != nil {
+			t.Fatalf("ReceivePart: %v", err)
+		}
+		if err := dec.ReceivePart(p); err != nil {
+			t.Fatalf("ReceivePart (duplicate): %v", err)
+		}
+	}
+
+	assertFountainResult(t, dec, payload)
+}
+
+func TestDecoderRejectsInvalidSeqNum(t *testing.T) {
+	dec := NewDecoder()
+	if err := dec.ReceivePart("ur:test/0-4/abcdabcd"); err == nil {
+		t.Fatalf("expected error for seqNum 0, got nil")
+	}
+}
@@ -0,0 +1,105 @@
+package bcur
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// walletInfoV1 is WalletInfo's schema-version-1 on-wire shape: a CBOR array
+// [derivationPath, chainCode, name, internal1, internal2, someBytes, xpub],
+// encoded and decoded positionally via the `toarray` struct tag instead of a
+// hand-rolled type-assertion walk.
+type walletInfoV1 struct {
+	_              struct{} `cbor:",toarray"`
+	DerivationPath string
+	ChainCode      string
+	Name           string
+	Internal1      bool
+	Internal2      bool
+	SomeBytes      string
+	XPub           string
+}
+
+// accountV1 is Account's schema-version-1 on-wire shape: a CBOR array
+// [id, index, type, block, wallet].
+type accountV1 struct {
+	_      struct{} `cbor:",toarray"`
+	ID     int
+	Index  int
+	Type   string
+	Block  int
+	Wallet walletInfoV1
+}
+
+// rootV1 is the schema-version-1 on-wire shape: a CBOR array
+// [version, accounts].
+type rootV1 struct {
+	_        struct{} `cbor:",toarray"`
+	Version  int
+	Accounts []accountV1
+}
+
+// schemaDecoders maps a payload's schema version (the first element of its
+// top-level CBOR array) to the function that decodes the rest of the array
+// for that version. Registering a new version here lets newer wallets add
+// fields without breaking readers still on an older version.
+var schemaDecoders = map[int]func([]byte) (Root, error){
+	1: decodeRootV1,
+}
+
+func decodeRootV1(data []byte) (Root, error) {
+	var v rootV1
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return Root{}, fmt.Errorf("bcur: schema v1: %w", err)
+	}
+	return v.toRoot(), nil
+}
+
+func (r rootV1) toRoot() Root {
+	accounts := make([]Account, len(r.Accounts))
+	for i, a := range r.Accounts {
+		accounts[i] = Account{
+			ID:    a.ID,
+			Index: a.Index,
+			Type:  a.Type,
+			Block: a.Block,
+			Wallet: WalletInfo{
+				DerivationPath: a.Wallet.DerivationPath,
+				ChainCode:      a.Wallet.ChainCode,
+				Name:           a.Wallet.Name,
+				Internal1:      a.Wallet.Internal1,
+				Internal2:      a.Wallet.Internal2,
+				SomeBytes:      a.Wallet.SomeBytes,
+				XPub:           a.Wallet.XPub,
+			},
+		}
+	}
+	return Root{Version: r.Version, Accounts: accounts}
+}
+
+// rootV1FromRoot converts a Root into its schema-version-1 on-wire shape for
+// encoding. Encode always uses the schema-version-1 layout and copies
+// root.Version into the version field unchanged; a Root whose Version has no
+// entry in schemaDecoders encodes, but Decode rejects the result.
+func rootV1FromRoot(root Root) rootV1 {
+	accounts := make([]accountV1, len(root.Accounts))
+	for i, a := range root.Accounts {
+		accounts[i] = accountV1{
+			ID:    a.ID,
+			Index: a.Index,
+			Type:  a.Type,
+			Block: a.Block,
+			Wallet: walletInfoV1{
+				DerivationPath: a.Wallet.DerivationPath,
+				ChainCode:      a.Wallet.ChainCode,
+				Name:           a.Wallet.Name,
+				Internal1:      a.Wallet.Internal1,
+				Internal2:      a.Wallet.Internal2,
+				SomeBytes:      a.Wallet.SomeBytes,
+				XPub:           a.Wallet.XPub,
+			},
+		}
+	}
+	return rootV1{Version: root.Version, Accounts: accounts}
+}
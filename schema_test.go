@@ -0,0 +1,24 @@
+package bcur
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+func TestDecodeSchemaUnsupportedVersion(t *testing.T) {
+	data, err := cbor.Marshal([]any{2, []any{}})
+	if err != nil {
+		t.Fatalf("cbor.Marshal: %v", err)
+	}
+
+	_, err = decodeSchema(data)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v (%T)", err, err)
+	}
+	if decodeErr.Field != "version" {
+		t.Fatalf("expected Field %q, got %q", "version", decodeErr.Field)
+	}
+}